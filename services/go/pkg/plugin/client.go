@@ -0,0 +1,129 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"sync"
+	"time"
+)
+
+// dialTimeout bounds how long Dial waits for a plugin to accept a
+// connection, so one unreachable endpoint can't stall cluster startup.
+const dialTimeout = 5 * time.Second
+
+// Client talks to a single plugin process over JSON-RPC. It redials once
+// on its own after a timeout tears down the connection; if that redial
+// fails, it keeps the closed connection and every later call returns an
+// error.
+type Client struct {
+	mutex   sync.Mutex
+	network string
+	address string
+	rpc     *rpc.Client
+}
+
+// Dial connects to a plugin listening on network ("unix" or "tcp") at
+// address.
+func Dial(network string, address string) (*Client, error) {
+	rpcClient, err := dial(network, address)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{network: network, address: address, rpc: rpcClient}, nil
+}
+
+func dial(network string, address string) (*rpc.Client, error) {
+	conn, err := net.DialTimeout(network, address, dialTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial plugin at %s:%s: %w", network, address, err)
+	}
+
+	return jsonrpc.NewClient(conn), nil
+}
+
+// Close closes the underlying connection.
+func (client *Client) Close() error {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+
+	return client.rpc.Close()
+}
+
+// redial tears down the current connection, if any, and replaces it
+// with a fresh one, so a client that tripped a timeout keeps working
+// for the next call. If the dial fails, the closed connection stays in
+// place.
+func (client *Client) redial() error {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+
+	client.rpc.Close()
+
+	rpcClient, err := dial(client.network, client.address)
+	if err != nil {
+		return err
+	}
+
+	client.rpc = rpcClient
+	return nil
+}
+
+// ListCommands asks the plugin which commands it claims.
+func (client *Client) ListCommands() ([]string, error) {
+	reply := ListCommandsReply{}
+
+	client.mutex.Lock()
+	rpcClient := client.rpc
+	client.mutex.Unlock()
+
+	if err := rpcClient.Call("Plugin.ListCommands", ListCommandsArgs{}, &reply); err != nil {
+		return nil, err
+	}
+
+	return reply.Commands, nil
+}
+
+// Invoke forwards a command invocation to the plugin.
+func (client *Client) Invoke(args InvokeArgs) (*InvokeReply, error) {
+	reply := InvokeReply{}
+
+	client.mutex.Lock()
+	rpcClient := client.rpc
+	client.mutex.Unlock()
+
+	if err := rpcClient.Call("Plugin.Invoke", args, &reply); err != nil {
+		return nil, err
+	}
+
+	return &reply, nil
+}
+
+// InvokeContext behaves like Invoke, but if ctx is canceled before the
+// plugin responds, it redials the underlying connection to unblock the
+// in-flight call instead of abandoning it. A caller that times out
+// repeatedly against one wedged plugin tears down and replaces that
+// connection each time rather than leaking a goroutine per call.
+func (client *Client) InvokeContext(ctx context.Context, args InvokeArgs) (*InvokeReply, error) {
+	client.mutex.Lock()
+	rpcClient := client.rpc
+	client.mutex.Unlock()
+
+	reply := &InvokeReply{}
+	call := rpcClient.Go("Plugin.Invoke", args, reply, make(chan *rpc.Call, 1))
+
+	select {
+	case <-call.Done:
+		if call.Error != nil {
+			return nil, call.Error
+		}
+
+		return reply, nil
+	case <-ctx.Done():
+		client.redial()
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,42 @@
+// Command echo is a reference cluster plugin: it claims a single
+// #echo command and replies with whatever arguments it was given. It
+// exists to show third parties the minimum needed to add a command
+// (tournaments, giveaways, map voting, ...) without touching core code.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/cfoust/sour/pkg/plugin"
+)
+
+type echoHandler struct{}
+
+func (echoHandler) ListCommands() []string {
+	return []string{"echo"}
+}
+
+func (echoHandler) Invoke(args plugin.InvokeArgs) plugin.InvokeReply {
+	return plugin.InvokeReply{
+		Handled:  true,
+		Response: fmt.Sprintf("echo: %s", strings.Join(args.Args, " ")),
+	}
+}
+
+func main() {
+	socket := flag.String("socket", "/tmp/sour-echo-plugin.sock", "unix socket to listen on")
+	flag.Parse()
+
+	listener, err := net.Listen("unix", *socket)
+	if err != nil {
+		panic(err)
+	}
+	defer listener.Close()
+
+	if err := plugin.Serve(listener, echoHandler{}); err != nil {
+		panic(err)
+	}
+}
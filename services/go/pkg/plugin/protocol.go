@@ -0,0 +1,37 @@
+// Package plugin defines the JSON-RPC protocol the cluster uses to
+// dispatch unrecognized chat commands to external plugin processes, and
+// a client library for talking to it.
+package plugin
+
+// ListCommandsArgs carries no data; a plugin always answers with its
+// full command list.
+type ListCommandsArgs struct{}
+
+// ListCommandsReply lists the "#names" a plugin wants to claim, without
+// the leading '#'.
+type ListCommandsReply struct {
+	Commands []string `json:"commands"`
+}
+
+// InvokeArgs describes a single command invocation forwarded from
+// RunCommand to a plugin. The json tags are the documented wire schema;
+// net/rpc/jsonrpc marshals this struct directly, so they must match
+// exactly for a non-Go plugin implementation to decode a request.
+type InvokeArgs struct {
+	Command   string   `json:"command"`
+	Args      []string `json:"args"`
+	UserID    string   `json:"user_id"`
+	Host      string   `json:"host"`
+	ServerRef string   `json:"server_ref"`
+	IsOwner   bool     `json:"is_owner"`
+}
+
+// InvokeReply is what a plugin hands back after handling (or declining)
+// a command. ServerCommands are forwarded verbatim to the user's
+// current game server via SendCommand. The json tags are the documented
+// wire schema; see InvokeArgs.
+type InvokeReply struct {
+	Handled        bool     `json:"handled"`
+	Response       string   `json:"response"`
+	ServerCommands []string `json:"server_commands"`
+}
@@ -0,0 +1,50 @@
+package plugin
+
+import (
+	"net"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+)
+
+// Handler is implemented by a plugin's command logic.
+type Handler interface {
+	// ListCommands returns the "#names" this plugin wants to claim,
+	// without the leading '#'.
+	ListCommands() []string
+	// Invoke handles a single command invocation.
+	Invoke(args InvokeArgs) InvokeReply
+}
+
+// adapter exposes a Handler under the method set net/rpc/jsonrpc
+// expects.
+type adapter struct {
+	handler Handler
+}
+
+func (a *adapter) ListCommands(args ListCommandsArgs, reply *ListCommandsReply) error {
+	reply.Commands = a.handler.ListCommands()
+	return nil
+}
+
+func (a *adapter) Invoke(args InvokeArgs, reply *InvokeReply) error {
+	*reply = a.handler.Invoke(args)
+	return nil
+}
+
+// Serve registers handler under the name "Plugin" and serves JSON-RPC
+// requests on every connection listener accepts, until it's closed.
+func Serve(listener net.Listener, handler Handler) error {
+	server := rpc.NewServer()
+	if err := server.RegisterName("Plugin", &adapter{handler: handler}); err != nil {
+		return err
+	}
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return err
+		}
+
+		go server.ServeCodec(jsonrpc.NewServerCodec(conn))
+	}
+}
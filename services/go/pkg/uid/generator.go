@@ -0,0 +1,55 @@
+// Package uid mints short, human-typeable identifiers from a small word
+// list. It is modeled on codies' uid.Generator.
+package uid
+
+import (
+	"math/rand"
+	"strings"
+)
+
+var words = []string{
+	"able", "acid", "aged", "also", "area", "army", "away", "baby", "back", "ball",
+	"band", "bank", "base", "bath", "bead", "beam", "bean", "bear", "beat", "been",
+	"beer", "bell", "belt", "best", "bike", "bird", "blue", "boat", "body", "bold",
+	"bolt", "bond", "bone", "book", "boom", "boot", "bore", "born", "boss", "both",
+	"bowl", "bulk", "burn", "bush", "busy", "cake", "call", "calm", "came", "camp",
+	"card", "care", "case", "cash", "cast", "cave", "cell", "chat", "chip", "city",
+	"club", "coal", "coat", "code", "cold", "come", "cook", "cool", "cope", "copy",
+	"core", "cost", "crew", "crop", "dark", "data", "date", "dawn", "days", "dead",
+	"deal", "dear", "debt", "deep", "deny", "desk", "dial", "diet", "disc", "dish",
+	"dive", "dock", "does", "done", "door", "dose", "down", "draw", "drop", "drum",
+}
+
+// Generator produces short codes by joining a fixed number of words
+// from the dictionary. It is salted per instance so that two cluster
+// processes sharing a reservation store don't converge on the same
+// sequence of codes.
+type Generator struct {
+	rng    *rand.Rand
+	length int
+}
+
+// New creates a Generator that produces codes made of `length` words,
+// seeded from salt (typically unique per cluster instance).
+func New(salt int64, length int) *Generator {
+	if length < 1 {
+		length = 1
+	}
+
+	return &Generator{
+		rng:    rand.New(rand.NewSource(salt)),
+		length: length,
+	}
+}
+
+// Generate returns a new candidate code. It does not check for
+// collisions; callers that need uniqueness (like rooms.Registry) should
+// retry against their own reservation table.
+func (generator *Generator) Generate() string {
+	parts := make([]string, generator.length)
+	for i := range parts {
+		parts[i] = words[generator.rng.Intn(len(words))]
+	}
+
+	return strings.Join(parts, "-")
+}
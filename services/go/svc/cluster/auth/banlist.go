@@ -0,0 +1,243 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// BanTarget describes which identifier a ban entry is keyed by.
+type BanTarget int
+
+const (
+	BanTargetIP BanTarget = iota
+	BanTargetFingerprint
+	BanTargetAuthName
+)
+
+func (target BanTarget) String() string {
+	switch target {
+	case BanTargetIP:
+		return "ip"
+	case BanTargetFingerprint:
+		return "fingerprint"
+	case BanTargetAuthName:
+		return "auth name"
+	default:
+		return "unknown"
+	}
+}
+
+// BanEntry records a single ban, along with who issued it and when it
+// expires (if ever).
+type BanEntry struct {
+	Target    BanTarget `json:"target"`
+	Key       string    `json:"key"`
+	Reason    string    `json:"reason,omitempty"`
+	IssuedBy  string    `json:"issued_by"`
+	IssuedAt  time.Time `json:"issued_at"`
+	ExpiresAt time.Time `json:"expires_at,omitempty"`
+}
+
+// IsExpired reports whether a timed ban has lapsed. Permanent bans (zero
+// ExpiresAt) are never expired.
+func (entry *BanEntry) IsExpired() bool {
+	return !entry.ExpiresAt.IsZero() && time.Now().After(entry.ExpiresAt)
+}
+
+// BanList is a persistent, in-memory store of bans keyed by IP, session
+// fingerprint, and Sour auth name. It is safe for concurrent use.
+type BanList struct {
+	mutex sync.RWMutex
+	path  string
+
+	byIP          map[string]*BanEntry
+	byFingerprint map[string]*BanEntry
+	byAuthName    map[string]*BanEntry
+}
+
+// NewBanList creates a BanList that persists to path. If path already
+// contains a ban list, it is loaded immediately.
+func NewBanList(path string) (*BanList, error) {
+	list := &BanList{
+		path:          path,
+		byIP:          make(map[string]*BanEntry),
+		byFingerprint: make(map[string]*BanEntry),
+		byAuthName:    make(map[string]*BanEntry),
+	}
+
+	if _, err := os.Stat(path); err == nil {
+		if err := list.load(); err != nil {
+			return nil, err
+		}
+	}
+
+	return list, nil
+}
+
+func (list *BanList) mapFor(target BanTarget) (map[string]*BanEntry, error) {
+	switch target {
+	case BanTargetIP:
+		return list.byIP, nil
+	case BanTargetFingerprint:
+		return list.byFingerprint, nil
+	case BanTargetAuthName:
+		return list.byAuthName, nil
+	default:
+		return nil, fmt.Errorf("unknown ban target %v", target)
+	}
+}
+
+// Ban records a new ban for the given target/key pair. A zero duration
+// means the ban never expires.
+func (list *BanList) Ban(target BanTarget, key string, issuedBy string, reason string, duration time.Duration) error {
+	list.mutex.Lock()
+	defer list.mutex.Unlock()
+
+	entries, err := list.mapFor(target)
+	if err != nil {
+		return err
+	}
+
+	entry := &BanEntry{
+		Target:   target,
+		Key:      key,
+		Reason:   reason,
+		IssuedBy: issuedBy,
+		IssuedAt: time.Now(),
+	}
+
+	if duration > 0 {
+		entry.ExpiresAt = entry.IssuedAt.Add(duration)
+	}
+
+	entries[key] = entry
+
+	return list.save()
+}
+
+// Unban removes a ban, if one exists. It is not an error to unban a key
+// that was never banned.
+func (list *BanList) Unban(target BanTarget, key string) error {
+	list.mutex.Lock()
+	defer list.mutex.Unlock()
+
+	entries, err := list.mapFor(target)
+	if err != nil {
+		return err
+	}
+
+	delete(entries, key)
+
+	return list.save()
+}
+
+// Check looks up all three identifiers for a connecting client and
+// returns the first ban still in effect, if any.
+func (list *BanList) Check(ip string, fingerprint string, authName string) (*BanEntry, bool) {
+	list.mutex.RLock()
+	defer list.mutex.RUnlock()
+
+	for _, lookup := range []struct {
+		key     string
+		entries map[string]*BanEntry
+	}{
+		{ip, list.byIP},
+		{fingerprint, list.byFingerprint},
+		{authName, list.byAuthName},
+	} {
+		if lookup.key == "" {
+			continue
+		}
+
+		entry, ok := lookup.entries[lookup.key]
+		if !ok || entry.IsExpired() {
+			continue
+		}
+
+		return entry, true
+	}
+
+	return nil, false
+}
+
+// List returns every active (non-expired) ban, for use by an audit
+// command like #banlist.
+func (list *BanList) List() []*BanEntry {
+	list.mutex.RLock()
+	defer list.mutex.RUnlock()
+
+	entries := make([]*BanEntry, 0)
+	for _, group := range []map[string]*BanEntry{list.byIP, list.byFingerprint, list.byAuthName} {
+		for _, entry := range group {
+			if entry.IsExpired() {
+				continue
+			}
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries
+}
+
+// persistedBanList is the on-disk JSON representation of a BanList.
+type persistedBanList struct {
+	IP          []*BanEntry `json:"ip"`
+	Fingerprint []*BanEntry `json:"fingerprint"`
+	AuthName    []*BanEntry `json:"auth_name"`
+}
+
+// save writes the ban list to disk. Callers must hold list.mutex.
+func (list *BanList) save() error {
+	if list.path == "" {
+		return nil
+	}
+
+	persisted := persistedBanList{}
+	for _, entry := range list.byIP {
+		persisted.IP = append(persisted.IP, entry)
+	}
+	for _, entry := range list.byFingerprint {
+		persisted.Fingerprint = append(persisted.Fingerprint, entry)
+	}
+	for _, entry := range list.byAuthName {
+		persisted.AuthName = append(persisted.AuthName, entry)
+	}
+
+	data, err := json.MarshalIndent(persisted, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal ban list: %w", err)
+	}
+
+	return os.WriteFile(list.path, data, 0644)
+}
+
+// load reads the ban list from disk. Callers must not hold list.mutex.
+func (list *BanList) load() error {
+	data, err := os.ReadFile(list.path)
+	if err != nil {
+		return fmt.Errorf("failed to read ban list: %w", err)
+	}
+
+	persisted := persistedBanList{}
+	if err := json.Unmarshal(data, &persisted); err != nil {
+		return fmt.Errorf("failed to parse ban list: %w", err)
+	}
+
+	list.mutex.Lock()
+	defer list.mutex.Unlock()
+
+	for _, entry := range persisted.IP {
+		list.byIP[entry.Key] = entry
+	}
+	for _, entry := range persisted.Fingerprint {
+		list.byFingerprint[entry.Key] = entry
+	}
+	for _, entry := range persisted.AuthName {
+		list.byAuthName[entry.Key] = entry
+	}
+
+	return nil
+}
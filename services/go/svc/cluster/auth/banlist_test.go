@@ -0,0 +1,98 @@
+package auth
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestBanEntryIsExpired(t *testing.T) {
+	entry := &BanEntry{}
+	if entry.IsExpired() {
+		t.Fatalf("a permanent ban (zero ExpiresAt) should never be expired")
+	}
+
+	entry.ExpiresAt = time.Now().Add(-time.Minute)
+	if !entry.IsExpired() {
+		t.Fatalf("expected a ban with a past ExpiresAt to be expired")
+	}
+
+	entry.ExpiresAt = time.Now().Add(time.Minute)
+	if entry.IsExpired() {
+		t.Fatalf("expected a ban with a future ExpiresAt to not be expired")
+	}
+}
+
+func TestBanListPersistsAcrossReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bans.json")
+
+	list, err := NewBanList(path)
+	if err != nil {
+		t.Fatalf("failed to create ban list: %v", err)
+	}
+
+	if err := list.Ban(BanTargetIP, "1.2.3.4", "admin", "griefing", time.Hour); err != nil {
+		t.Fatalf("failed to ban: %v", err)
+	}
+
+	reloaded, err := NewBanList(path)
+	if err != nil {
+		t.Fatalf("failed to reload ban list: %v", err)
+	}
+
+	entry, banned := reloaded.Check("1.2.3.4", "", "")
+	if !banned {
+		t.Fatalf("expected reloaded ban list to still have the ban")
+	}
+	if entry.IssuedBy != "admin" {
+		t.Fatalf("expected issuer to survive reload, got %q", entry.IssuedBy)
+	}
+
+	if err := reloaded.Unban(BanTargetIP, "1.2.3.4"); err != nil {
+		t.Fatalf("failed to unban: %v", err)
+	}
+
+	if _, banned := reloaded.Check("1.2.3.4", "", ""); banned {
+		t.Fatalf("expected ban to be gone after Unban")
+	}
+}
+
+func TestBanListExpiredEntriesAreNotEnforced(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bans.json")
+
+	list, err := NewBanList(path)
+	if err != nil {
+		t.Fatalf("failed to create ban list: %v", err)
+	}
+
+	if err := list.Ban(BanTargetAuthName, "grief3r", "admin", "", time.Millisecond); err != nil {
+		t.Fatalf("failed to ban: %v", err)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+
+	if _, banned := list.Check("", "", "grief3r"); banned {
+		t.Fatalf("expected expired ban to no longer be enforced")
+	}
+}
+
+func TestBanListChecksAllThreeIdentifiers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bans.json")
+
+	list, err := NewBanList(path)
+	if err != nil {
+		t.Fatalf("failed to create ban list: %v", err)
+	}
+
+	if err := list.Ban(BanTargetFingerprint, "abc123", "admin", "", 0); err != nil {
+		t.Fatalf("failed to ban: %v", err)
+	}
+
+	if _, banned := list.Check("9.9.9.9", "abc123", "someone"); !banned {
+		t.Fatalf("expected a matching fingerprint to be caught even with a clean IP and auth name")
+	}
+
+	if _, banned := list.Check("9.9.9.9", "", "someone"); banned {
+		t.Fatalf("expected no ban when none of the three identifiers match")
+	}
+}
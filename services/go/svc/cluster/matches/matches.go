@@ -0,0 +1,333 @@
+// Package matches implements cluster-wide matchmaking: named queues with
+// party size and map pool limits, Elo-style skill buckets, and a
+// ready-check before a match is handed off to a freshly spawned server.
+package matches
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cfoust/sour/svc/cluster/servers"
+
+	"github.com/rs/zerolog/log"
+)
+
+const readyCheckTimeout = 15 * time.Second
+
+// QueueConfig describes one named queue, e.g. "1v1 insta" or "3v3 ctf",
+// as declared in cluster config.
+type QueueConfig struct {
+	Name         string
+	MinPartySize int
+	MaxPartySize int
+	Maps         []string
+	Mode         int
+	Preset       string
+}
+
+// Player is the minimal interface Matches needs from a connected user.
+// It mirrors the subset of *service.User that matchmaking touches, so
+// this package doesn't import service and create a cycle.
+type Player interface {
+	SendServerMessage(message string)
+	AuthID(ctx context.Context) (string, error)
+}
+
+// ServerSpawner is the minimal interface Matches needs to stand up a
+// server for a matched party. It's satisfied by *servers.Manager; tests
+// fake it instead of constructing a real Manager.
+type ServerSpawner interface {
+	NewServer(ctx context.Context, preset string, dedicated bool) (*servers.GameServer, error)
+}
+
+type queuedPlayer struct {
+	player   Player
+	rating   int
+	ready    bool
+	queuedAt time.Time
+}
+
+type readyCheck struct {
+	cancel  context.CancelFunc
+	players []*queuedPlayer
+}
+
+// Matches owns every queue's pending players, in-flight ready checks,
+// and the rating store used to bucket players by skill.
+type Matches struct {
+	mutex sync.Mutex
+
+	configs      map[string]QueueConfig
+	defaultQueue string
+	queues       map[string][]*queuedPlayer
+	checks       map[string]*readyCheck
+
+	manager ServerSpawner
+	ratings *RatingStore
+	sink    ResultsSink
+}
+
+// New creates a Matches subsystem from the queues declared in cluster
+// config. ratingsPath is the SQLite database used to persist per-player
+// Elo ratings; sink receives an event when a matched server starts and
+// another when ReportResult records the outcome.
+func New(configs []QueueConfig, manager ServerSpawner, ratingsPath string, sink ResultsSink) (*Matches, error) {
+	ratings, err := NewRatingStore(ratingsPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open rating store: %w", err)
+	}
+
+	byName := make(map[string]QueueConfig, len(configs))
+	for _, config := range configs {
+		byName[config.Name] = config
+	}
+
+	defaultQueue := ""
+	if len(configs) > 0 {
+		defaultQueue = configs[0].Name
+	}
+
+	if sink == nil {
+		sink = NoopResultsSink{}
+	}
+
+	return &Matches{
+		configs:      byName,
+		defaultQueue: defaultQueue,
+		queues:       make(map[string][]*queuedPlayer),
+		checks:       make(map[string]*readyCheck),
+		manager:      manager,
+		ratings:      ratings,
+		sink:         sink,
+	}, nil
+}
+
+// Queue adds player to the named queue. An empty name falls back to the
+// first configured queue, preserving the old #duel behavior of not
+// requiring an argument.
+func (matches *Matches) Queue(player Player, name string) error {
+	matches.mutex.Lock()
+	defer matches.mutex.Unlock()
+
+	config, ok := matches.resolve(name)
+	if !ok {
+		return fmt.Errorf("queue '%s' does not exist", name)
+	}
+
+	rating := startingRating
+	if authID, err := player.AuthID(context.Background()); err == nil && authID != "" {
+		if fetched, err := matches.ratings.Rating(authID); err == nil {
+			rating = fetched
+		}
+	}
+
+	matches.dequeueLocked(player)
+	matches.queues[config.Name] = append(matches.queues[config.Name], &queuedPlayer{
+		player:   player,
+		rating:   rating,
+		queuedAt: time.Now(),
+	})
+
+	matches.tryStartReadyCheckLocked(config.Name)
+
+	return nil
+}
+
+// Dequeue removes player from whichever queue they're waiting in, if
+// any.
+func (matches *Matches) Dequeue(player Player) {
+	matches.mutex.Lock()
+	defer matches.mutex.Unlock()
+
+	matches.dequeueLocked(player)
+}
+
+func (matches *Matches) dequeueLocked(player Player) {
+	for name, queued := range matches.queues {
+		for i, entry := range queued {
+			if entry.player != player {
+				continue
+			}
+
+			matches.queues[name] = append(queued[:i], queued[i+1:]...)
+			return
+		}
+	}
+}
+
+// Status describes which queue player is in and how many others are
+// waiting alongside them, for the #status command.
+func (matches *Matches) Status(player Player) string {
+	matches.mutex.Lock()
+	defer matches.mutex.Unlock()
+
+	for name, queued := range matches.queues {
+		for _, entry := range queued {
+			if entry.player == player {
+				return fmt.Sprintf("queued for '%s' with %d other player(s)", name, len(queued)-1)
+			}
+		}
+	}
+
+	return "not currently queued"
+}
+
+// ReportResult updates both players' ratings following a completed
+// match and forwards the outcome to the configured results sink. It's
+// the call site that makes the rating store (and the skill-bucketed
+// sort in tryStartReadyCheckLocked) actually mean something; something
+// with visibility into match outcomes (an admin command today, a game
+// server webhook or plugin later) is expected to call it.
+func (matches *Matches) ReportResult(queue string, winnerAuthID string, loserAuthID string) error {
+	if err := matches.ratings.RecordResult(winnerAuthID, loserAuthID); err != nil {
+		return fmt.Errorf("failed to update ratings: %w", err)
+	}
+
+	matches.sink.RecordResult(MatchResult{
+		Queue:   queue,
+		EndedAt: time.Now(),
+		Winner:  winnerAuthID,
+		Loser:   loserAuthID,
+	})
+
+	return nil
+}
+
+// Ready marks player as ready during an active ready-check.
+func (matches *Matches) Ready(player Player) error {
+	matches.mutex.Lock()
+	defer matches.mutex.Unlock()
+
+	for _, check := range matches.checks {
+		for _, entry := range check.players {
+			if entry.player == player {
+				entry.ready = true
+				return nil
+			}
+		}
+	}
+
+	return fmt.Errorf("you are not in a ready check")
+}
+
+// resolve looks up name, falling back to the first queue declared in
+// cluster config (not map iteration, which randomizes) when name is
+// empty, preserving the old #duel behavior of not requiring an
+// argument.
+func (matches *Matches) resolve(name string) (QueueConfig, bool) {
+	if name == "" {
+		name = matches.defaultQueue
+	}
+
+	config, ok := matches.configs[name]
+	return config, ok
+}
+
+// tryStartReadyCheckLocked spawns a ready check once a queue has enough
+// players for its minimum party size. Callers must hold matches.mutex.
+func (matches *Matches) tryStartReadyCheckLocked(name string) {
+	config := matches.configs[name]
+
+	if _, inProgress := matches.checks[name]; inProgress {
+		return
+	}
+
+	queued := matches.queues[name]
+	if len(queued) < config.MinPartySize {
+		return
+	}
+
+	// Sort by rating so the players taken for this round form the
+	// tightest skill bracket available in the queue.
+	sort.Slice(queued, func(i, j int) bool {
+		return queued[i].rating < queued[j].rating
+	})
+
+	size := config.MaxPartySize
+	if size <= 0 || size > len(queued) {
+		size = len(queued)
+	}
+
+	entries := queued[:size]
+	matches.queues[name] = queued[size:]
+
+	ctx, cancel := context.WithTimeout(context.Background(), readyCheckTimeout)
+	check := &readyCheck{cancel: cancel, players: entries}
+	matches.checks[name] = check
+
+	for _, entry := range entries {
+		entry.player.SendServerMessage(fmt.Sprintf("match found for '%s' — type #ready within 15 seconds", name))
+	}
+
+	go matches.runReadyCheck(ctx, name, check)
+}
+
+func (matches *Matches) runReadyCheck(ctx context.Context, name string, check *readyCheck) {
+	<-ctx.Done()
+
+	matches.mutex.Lock()
+	defer matches.mutex.Unlock()
+
+	delete(matches.checks, name)
+
+	ready := make([]*queuedPlayer, 0, len(check.players))
+	for _, entry := range check.players {
+		if entry.ready {
+			ready = append(ready, entry)
+		} else {
+			entry.player.SendServerMessage("you didn't ready up in time and were removed from the queue")
+		}
+	}
+
+	if len(ready) == len(check.players) {
+		matches.startMatch(name, ready)
+		return
+	}
+
+	for _, entry := range ready {
+		entry.player.SendServerMessage("not everyone readied up — you've been requeued")
+		entry.queuedAt = time.Now()
+	}
+
+	matches.queues[name] = append(ready, matches.queues[name]...)
+	matches.tryStartReadyCheckLocked(name)
+}
+
+// startMatch spawns a server for the matched players. Callers must hold
+// matches.mutex.
+func (matches *Matches) startMatch(name string, players []*queuedPlayer) {
+	config := matches.configs[name]
+
+	gameServer, err := matches.manager.NewServer(context.Background(), config.Preset, false)
+	if err != nil {
+		log.Error().Err(err).Str("queue", name).Msg("failed to start matched server")
+		for _, entry := range players {
+			entry.player.SendServerMessage("failed to start your match, you've been requeued")
+		}
+		matches.queues[name] = append(players, matches.queues[name]...)
+		return
+	}
+
+	// Apply the queue's configured map pool and mode, the same way
+	// #creategame applies an explicit map/mode request.
+	if len(config.Maps) > 0 {
+		mapName := config.Maps[rand.Intn(len(config.Maps))]
+		gameServer.SendCommand(fmt.Sprintf("changemap %s %d", mapName, config.Mode))
+	} else if config.Mode > 0 {
+		gameServer.SendCommand(fmt.Sprintf("setmode %d", config.Mode))
+	}
+
+	for _, entry := range players {
+		entry.player.SendServerMessage(fmt.Sprintf("your match is ready: #join %s", gameServer.Reference()))
+	}
+
+	matches.sink.RecordResult(MatchResult{
+		Queue:     name,
+		StartedAt: time.Now(),
+		Server:    gameServer.Reference(),
+	})
+}
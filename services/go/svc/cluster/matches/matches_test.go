@@ -0,0 +1,104 @@
+package matches
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cfoust/sour/svc/cluster/servers"
+)
+
+// fakeSpawner never actually spawns a server; the ready-check test below
+// never lets startMatch be reached, so it only needs to satisfy
+// ServerSpawner.
+type fakeSpawner struct{}
+
+func (fakeSpawner) NewServer(ctx context.Context, preset string, dedicated bool) (*servers.GameServer, error) {
+	return nil, fmt.Errorf("fakeSpawner should not be called in this test")
+}
+
+// fakePlayer records the messages it's sent and reports a fixed auth ID.
+type fakePlayer struct {
+	authID   string
+	messages []string
+}
+
+func (player *fakePlayer) SendServerMessage(message string) {
+	player.messages = append(player.messages, message)
+}
+
+func (player *fakePlayer) AuthID(ctx context.Context) (string, error) {
+	return player.authID, nil
+}
+
+func newTestMatches(t *testing.T) *Matches {
+	t.Helper()
+
+	configs := []QueueConfig{{Name: "duel", MinPartySize: 2, MaxPartySize: 2}}
+
+	instance, err := New(configs, fakeSpawner{}, ":memory:", nil)
+	if err != nil {
+		t.Fatalf("failed to create matches: %v", err)
+	}
+
+	return instance
+}
+
+func TestQueueAndDequeueRoundTrip(t *testing.T) {
+	matches := newTestMatches(t)
+	player := &fakePlayer{authID: "alice"}
+
+	if status := matches.Status(player); status != "not currently queued" {
+		t.Fatalf("expected player to start unqueued, got %q", status)
+	}
+
+	if err := matches.Queue(player, "duel"); err != nil {
+		t.Fatalf("failed to queue: %v", err)
+	}
+
+	if status := matches.Status(player); status == "not currently queued" {
+		t.Fatalf("expected player to be queued after Queue")
+	}
+
+	matches.Dequeue(player)
+
+	if status := matches.Status(player); status != "not currently queued" {
+		t.Fatalf("expected player to be unqueued after Dequeue, got %q", status)
+	}
+}
+
+func TestReadyCheckRequeuesReadyPlayersAndDropsTheRest(t *testing.T) {
+	matches := newTestMatches(t)
+
+	ready := &fakePlayer{authID: "ready"}
+	unready := &fakePlayer{authID: "unready"}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	check := &readyCheck{
+		cancel: cancel,
+		players: []*queuedPlayer{
+			{player: ready, ready: true, queuedAt: time.Now()},
+			{player: unready, ready: false, queuedAt: time.Now()},
+		},
+	}
+
+	matches.mutex.Lock()
+	matches.checks["duel"] = check
+	matches.mutex.Unlock()
+
+	// Cancel immediately so runReadyCheck proceeds without waiting out
+	// readyCheckTimeout; since unready never readied up, not everyone is
+	// ready and startMatch (which needs a real ServerSpawner) is never
+	// reached.
+	cancel()
+	matches.runReadyCheck(ctx, "duel", check)
+
+	if status := matches.Status(ready); status == "not currently queued" {
+		t.Fatalf("expected the ready player to be requeued")
+	}
+
+	if status := matches.Status(unready); status != "not currently queued" {
+		t.Fatalf("expected the player who didn't ready up to be dropped, got %q", status)
+	}
+}
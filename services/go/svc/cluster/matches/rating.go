@@ -0,0 +1,103 @@
+package matches
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const startingRating = 1200
+
+// kFactor controls how far a single match can move a rating.
+const kFactor = 32
+
+// RatingStore persists a simple Elo-style rating per Sour auth identity
+// so queues can bucket players by skill.
+type RatingStore struct {
+	db *sql.DB
+}
+
+// NewRatingStore opens (and if necessary creates) the SQLite database
+// at path.
+func NewRatingStore(path string) (*RatingStore, error) {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS ratings (
+			auth_id TEXT PRIMARY KEY,
+			rating  INTEGER NOT NULL
+		)
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize ratings table: %w", err)
+	}
+
+	return &RatingStore{db: db}, nil
+}
+
+// Rating returns the current rating for authID, defaulting new players
+// to startingRating.
+func (store *RatingStore) Rating(authID string) (int, error) {
+	row := store.db.QueryRow(`SELECT rating FROM ratings WHERE auth_id = ?`, authID)
+
+	var rating int
+	err := row.Scan(&rating)
+	if err == sql.ErrNoRows {
+		return startingRating, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return rating, nil
+}
+
+// RecordResult updates winnerID and loserID's ratings following the
+// standard Elo update rule.
+func (store *RatingStore) RecordResult(winnerID string, loserID string) error {
+	winnerRating, err := store.Rating(winnerID)
+	if err != nil {
+		return err
+	}
+
+	loserRating, err := store.Rating(loserID)
+	if err != nil {
+		return err
+	}
+
+	expectedWinner := 1.0 / (1.0 + math.Pow(10, (float64(loserRating)-float64(winnerRating))/400))
+	expectedLoser := 1.0 - expectedWinner
+
+	newWinnerRating := winnerRating + int(kFactor*(1-expectedWinner))
+	newLoserRating := loserRating + int(kFactor*(0-expectedLoser))
+
+	return store.set(winnerID, newWinnerRating, loserID, newLoserRating)
+}
+
+func (store *RatingStore) set(firstID string, firstRating int, secondID string, secondRating int) error {
+	tx, err := store.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	for _, update := range []struct {
+		id     string
+		rating int
+	}{{firstID, firstRating}, {secondID, secondRating}} {
+		_, err := tx.Exec(`
+			INSERT INTO ratings (auth_id, rating) VALUES (?, ?)
+			ON CONFLICT(auth_id) DO UPDATE SET rating = excluded.rating
+		`, update.id, update.rating)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,51 @@
+package matches
+
+import "testing"
+
+func TestRecordResultMovesRatingsTowardTheOutcome(t *testing.T) {
+	store, err := NewRatingStore(":memory:")
+	if err != nil {
+		t.Fatalf("failed to open rating store: %v", err)
+	}
+
+	winnerBefore, err := store.Rating("winner")
+	if err != nil {
+		t.Fatalf("failed to read winner rating: %v", err)
+	}
+	loserBefore, err := store.Rating("loser")
+	if err != nil {
+		t.Fatalf("failed to read loser rating: %v", err)
+	}
+
+	if winnerBefore != startingRating || loserBefore != startingRating {
+		t.Fatalf("expected both players to start at %d, got %d and %d", startingRating, winnerBefore, loserBefore)
+	}
+
+	if err := store.RecordResult("winner", "loser"); err != nil {
+		t.Fatalf("failed to record result: %v", err)
+	}
+
+	winnerAfter, err := store.Rating("winner")
+	if err != nil {
+		t.Fatalf("failed to read winner rating: %v", err)
+	}
+	loserAfter, err := store.Rating("loser")
+	if err != nil {
+		t.Fatalf("failed to read loser rating: %v", err)
+	}
+
+	if winnerAfter <= winnerBefore {
+		t.Fatalf("expected winner rating to increase, went from %d to %d", winnerBefore, winnerAfter)
+	}
+	if loserAfter >= loserBefore {
+		t.Fatalf("expected loser rating to decrease, went from %d to %d", loserBefore, loserAfter)
+	}
+
+	// Two equally-rated players should move by the same amount in
+	// opposite directions.
+	winnerGain := winnerAfter - winnerBefore
+	loserLoss := loserBefore - loserAfter
+	if winnerGain != loserLoss {
+		t.Fatalf("expected symmetric movement for equally-rated players, winner gained %d but loser only lost %d", winnerGain, loserLoss)
+	}
+}
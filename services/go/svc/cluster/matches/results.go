@@ -0,0 +1,42 @@
+package matches
+
+import (
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// MatchResult describes a completed (or just-started) match, emitted so
+// downstream stats/history queries can pick it up, similar to the
+// "recent lobbies" pattern.
+type MatchResult struct {
+	Queue     string
+	Server    string
+	StartedAt time.Time
+	EndedAt   time.Time
+	Winner    string
+	Loser     string
+}
+
+// ResultsSink receives match result events. Implementations might write
+// to a database, a metrics system, or a chat channel.
+type ResultsSink interface {
+	RecordResult(result MatchResult)
+}
+
+// NoopResultsSink discards every result. It's the default when a
+// cluster doesn't configure one.
+type NoopResultsSink struct{}
+
+func (NoopResultsSink) RecordResult(result MatchResult) {}
+
+// LoggingResultsSink just logs results, useful until a real stats
+// pipeline exists.
+type LoggingResultsSink struct{}
+
+func (LoggingResultsSink) RecordResult(result MatchResult) {
+	log.Info().
+		Str("queue", result.Queue).
+		Str("server", result.Server).
+		Msg("match result recorded")
+}
@@ -0,0 +1,75 @@
+// Package plugins manages external command-handler processes that the
+// cluster dispatches unrecognized chat commands to over JSON-RPC, so
+// operators can add features without recompiling core code.
+package plugins
+
+import (
+	"sync"
+
+	"github.com/cfoust/sour/pkg/plugin"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Endpoint describes one plugin to connect to, as declared in cluster
+// config.
+type Endpoint struct {
+	Network string // "unix" or "tcp"
+	Address string
+}
+
+// Registry owns a connection to every configured plugin and knows which
+// commands each one claims.
+type Registry struct {
+	clients map[string]*plugin.Client // command name -> owning plugin
+}
+
+// Load dials every endpoint concurrently, asks each one which commands
+// it claims via ListCommands, and indexes the result. A plugin that
+// fails to connect or answer is logged and skipped rather than failing
+// cluster startup; Dial itself is timeout-bounded, so one unreachable
+// endpoint can't stall the others.
+func Load(endpoints []Endpoint) *Registry {
+	registry := &Registry{clients: make(map[string]*plugin.Client)}
+
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+
+	for _, endpoint := range endpoints {
+		endpoint := endpoint
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			client, err := plugin.Dial(endpoint.Network, endpoint.Address)
+			if err != nil {
+				log.Error().Err(err).Str("address", endpoint.Address).Msg("failed to connect to plugin")
+				return
+			}
+
+			commands, err := client.ListCommands()
+			if err != nil {
+				log.Error().Err(err).Str("address", endpoint.Address).Msg("failed to list plugin commands")
+				client.Close()
+				return
+			}
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			for _, command := range commands {
+				registry.clients[command] = client
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return registry
+}
+
+// Find returns the plugin claiming command, if any.
+func (registry *Registry) Find(command string) (*plugin.Client, bool) {
+	client, ok := registry.clients[command]
+	return client, ok
+}
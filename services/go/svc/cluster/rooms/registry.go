@@ -0,0 +1,218 @@
+// Package rooms mints and tracks short, shareable room codes for game
+// servers, so players can join with a memorable link instead of a raw
+// server ID.
+package rooms
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cfoust/sour/pkg/uid"
+	"github.com/cfoust/sour/svc/cluster/servers"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// DefaultTTL is how long a room code stays reserved without an
+	// active game server before it's reaped.
+	DefaultTTL = 30 * time.Minute
+	pruneEvery = 5 * time.Minute
+)
+
+// Reservation ties a room code to the game server it points at.
+type Reservation struct {
+	Code       string
+	GameServer *servers.GameServer
+	Host       string
+	Public     bool
+	ExpiresAt  time.Time
+}
+
+// Registry mints and tracks room code reservations. It is safe for
+// concurrent use.
+type Registry struct {
+	mutex sync.RWMutex
+
+	baseURL   string
+	ttl       time.Duration
+	generator *uid.Generator
+
+	byCode   map[string]*Reservation
+	byServer map[string]*Reservation
+
+	doPrune chan struct{}
+}
+
+// NewRegistry creates a Registry that mints codes of the given word
+// length, salted by salt (typically unique per cluster instance), and
+// builds join links under baseURL (e.g. "https://sour.example.com").
+func NewRegistry(baseURL string, salt int64, codeWords int) *Registry {
+	return &Registry{
+		baseURL:   baseURL,
+		ttl:       DefaultTTL,
+		generator: uid.New(salt, codeWords),
+		byCode:    make(map[string]*Reservation),
+		byServer:  make(map[string]*Reservation),
+		doPrune:   make(chan struct{}, 1),
+	}
+}
+
+// Start runs the reservation reaper until ctx is canceled. It prunes
+// stale rooms every five minutes, or immediately whenever Prune is
+// called.
+func (registry *Registry) Start(ctx context.Context) {
+	ticker := time.NewTicker(pruneEvery)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			registry.prune()
+		case <-registry.doPrune:
+			registry.prune()
+		}
+	}
+}
+
+// Prune requests an out-of-band sweep for stale rooms, without waiting
+// for the next ticker tick.
+func (registry *Registry) Prune() {
+	select {
+	case registry.doPrune <- struct{}{}:
+	default:
+	}
+}
+
+func (registry *Registry) prune() {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	now := time.Now()
+	for code, reservation := range registry.byCode {
+		if now.Before(reservation.ExpiresAt) || reservation.GameServer.IsRunning() {
+			continue
+		}
+
+		log.Info().Str("code", code).Msg("reaping stale room code")
+		delete(registry.byCode, code)
+		delete(registry.byServer, reservation.GameServer.Reference())
+	}
+}
+
+// Reserve claims a specific code for gameServer. It fails if the code
+// is already taken by an unexpired reservation.
+func (registry *Registry) Reserve(code string, gameServer *servers.GameServer, host string, public bool) (*Reservation, error) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	if existing, ok := registry.byCode[code]; ok && time.Now().Before(existing.ExpiresAt) {
+		return nil, fmt.Errorf("room code '%s' is already taken", code)
+	}
+
+	reservation := &Reservation{
+		Code:       code,
+		GameServer: gameServer,
+		Host:       host,
+		Public:     public,
+		ExpiresAt:  time.Now().Add(registry.ttl),
+	}
+
+	registry.set(reservation)
+
+	return reservation, nil
+}
+
+// Generate mints a fresh, unused code for gameServer, retrying until it
+// finds one that isn't currently reserved.
+func (registry *Registry) Generate(gameServer *servers.GameServer, host string, public bool) *Reservation {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	var code string
+	for {
+		code = registry.generator.Generate()
+		if _, taken := registry.byCode[code]; !taken {
+			break
+		}
+	}
+
+	reservation := &Reservation{
+		Code:       code,
+		GameServer: gameServer,
+		Host:       host,
+		Public:     public,
+		ExpiresAt:  time.Now().Add(registry.ttl),
+	}
+
+	registry.set(reservation)
+
+	return reservation
+}
+
+// set stores a reservation under both indices. Callers must hold
+// registry.mutex.
+func (registry *Registry) set(reservation *Reservation) {
+	registry.byCode[reservation.Code] = reservation
+	registry.byServer[reservation.GameServer.Reference()] = reservation
+}
+
+// Release removes a reservation immediately, e.g. when its server
+// shuts down.
+func (registry *Registry) Release(code string) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	reservation, ok := registry.byCode[code]
+	if !ok {
+		return
+	}
+
+	delete(registry.byCode, code)
+	delete(registry.byServer, reservation.GameServer.Reference())
+}
+
+// Get looks up a reservation by its room code.
+func (registry *Registry) Get(code string) (*Reservation, bool) {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+
+	reservation, ok := registry.byCode[code]
+	return reservation, ok
+}
+
+// GetByServer looks up the reservation (if any) belonging to a running
+// game server, keyed by its reference string.
+func (registry *Registry) GetByServer(reference string) (*Reservation, bool) {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+
+	reservation, ok := registry.byServer[reference]
+	return reservation, ok
+}
+
+// List returns every active public room, for use by #rooms and the
+// /api/rooms HTTP endpoint.
+func (registry *Registry) List() []*Reservation {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+
+	rooms := make([]*Reservation, 0)
+	for _, reservation := range registry.byCode {
+		if !reservation.Public {
+			continue
+		}
+		rooms = append(rooms, reservation)
+	}
+
+	return rooms
+}
+
+// URL builds the canonical, shareable join link for a room code.
+func (registry *Registry) URL(code string) string {
+	return fmt.Sprintf("%s/#%s", registry.baseURL, code)
+}
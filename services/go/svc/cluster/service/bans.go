@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cfoust/sour/svc/cluster/auth"
+)
+
+// IsBanned looks up a connection's host, fingerprint, and auth name
+// against the ban list.
+func (server *Cluster) IsBanned(ip string, fingerprint string, authName string) (*auth.BanEntry, bool) {
+	return server.bans.Check(ip, fingerprint, authName)
+}
+
+// Authorize returns an error if any of the given identifiers is actively
+// banned. It takes bare identifiers rather than a *User, so it can be
+// called before a User exists; an empty identifier is not checked. Its
+// only caller is refuseIfBanned, which runs when a user issues a
+// command: connections are not checked against the ban list when they
+// are accepted or handed off to a game server.
+func (server *Cluster) Authorize(ip string, fingerprint string, authName string) error {
+	entry, banned := server.IsBanned(ip, fingerprint, authName)
+	if !banned {
+		return nil
+	}
+
+	if entry.Reason != "" {
+		return fmt.Errorf("you are banned: %s", entry.Reason)
+	}
+
+	return fmt.Errorf("you are banned")
+}
+
+// refuseIfBanned checks user's host, fingerprint, and auth name against
+// the ban list and returns an error if any of them are actively banned.
+// RunCommand calls this before doing anything else, so a banned user,
+// including one banned mid-session, is cut off on their next command.
+func (server *Cluster) refuseIfBanned(ctx context.Context, user *User) error {
+	authID, _ := user.AuthID(ctx)
+
+	return server.Authorize(user.Connection.Host(), user.Connection.Fingerprint(), authID)
+}
+
+// IsAdmin reports whether user has cluster-operator privileges. This is
+// distinct from IsOwner (see the "edit" command), which only means
+// "owns the space/server they're currently in" — something every
+// player gets for free via #creategame. Commands that mutate
+// cluster-wide state, like bans, must require this instead.
+func (server *Cluster) IsAdmin(ctx context.Context, user *User) (bool, error) {
+	authID, err := user.AuthID(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	if authID == "" {
+		return false, nil
+	}
+
+	return server.admins[authID], nil
+}
@@ -4,10 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"strings"
 	"time"
 
 	"github.com/cfoust/sour/pkg/game"
+	"github.com/cfoust/sour/svc/cluster/auth"
 	"github.com/cfoust/sour/svc/cluster/ingress"
 	"github.com/cfoust/sour/svc/cluster/servers"
 
@@ -15,13 +17,43 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
+// banTargetFor figures out which kind of identifier a moderator typed:
+// an IP address if it parses as one, a connection fingerprint if
+// prefixed with "fp:" (the only one of the three identifiers with no
+// recognizable shape of its own), otherwise a Sour auth name. It
+// returns the target type along with the key to store, with any "fp:"
+// prefix stripped.
+func banTargetFor(key string) (auth.BanTarget, string) {
+	if net.ParseIP(key) != nil {
+		return auth.BanTargetIP, key
+	}
+
+	if strings.HasPrefix(key, "fp:") {
+		return auth.BanTargetFingerprint, strings.TrimPrefix(key, "fp:")
+	}
+
+	return auth.BanTargetAuthName, key
+}
+
+// roomCodeFor returns the shareable room code for gameServer, falling
+// back to its raw ID if no reservation has been made yet.
+func (server *Cluster) roomCodeFor(gameServer *servers.GameServer) string {
+	if reservation, ok := server.rooms.GetByServer(gameServer.Reference()); ok {
+		return reservation.Code
+	}
+
+	return gameServer.Id
+}
+
 func (server *Cluster) GivePrivateMatchHelp(ctx context.Context, user *User, gameServer *servers.GameServer) {
 	tick := time.NewTicker(30 * time.Second)
 
-	message := fmt.Sprintf("This is your private server. Have other players join by saying '#join %s' in any Sour server.", gameServer.Id)
+	code := server.roomCodeFor(gameServer)
+
+	message := fmt.Sprintf("This is your private server. Have other players join by saying '#join %s' in any Sour server.", code)
 
 	if user.Connection.Type() == ingress.ClientTypeWS {
-		message = fmt.Sprintf("This is your private server. Have other players join by saying '#join %s' in any Sour server or by sending the link in your URL bar. (We also copied it for you!)", gameServer.Id)
+		message = fmt.Sprintf("This is your private server. Have other players join by saying '#join %s' in any Sour server, or by visiting %s. (We also copied it for you!)", code, server.rooms.URL(code))
 	}
 
 	sessionContext := user.ServerSessionContext()
@@ -108,6 +140,10 @@ func (server *Cluster) RunCommand(ctx context.Context, command string, user *Use
 		return false, "", errors.New("invalid command")
 	}
 
+	if err := server.refuseIfBanned(ctx, user); err != nil {
+		return true, "", err
+	}
+
 	switch args[0] {
 	case "creategame":
 		params := &CreateParams{}
@@ -116,6 +152,8 @@ func (server *Cluster) RunCommand(ctx context.Context, command string, user *Use
 			if err != nil {
 				return true, "", err
 			}
+		} else {
+			params = server.defaultCreateParams(ctx, user)
 		}
 
 		server.createMutex.Lock()
@@ -128,6 +166,9 @@ func (server *Cluster) RunCommand(ctx context.Context, command string, user *Use
 
 		existingServer, hasExistingServer := server.hostServers[user.Connection.Host()]
 		if hasExistingServer {
+			if reservation, ok := server.rooms.GetByServer(existingServer.Reference()); ok {
+				server.rooms.Release(reservation.Code)
+			}
 			server.manager.RemoveServer(existingServer)
 		}
 
@@ -162,6 +203,7 @@ func (server *Cluster) RunCommand(ctx context.Context, command string, user *Use
 
 		server.lastCreate[user.Connection.Host()] = time.Now()
 		server.hostServers[user.Connection.Host()] = gameServer
+		server.rooms.Generate(gameServer, user.Connection.Host(), false)
 
 		connected, err := user.ConnectToServer(gameServer, "", false, true)
 		go server.GivePrivateMatchHelp(server.serverCtx, user, user.Server)
@@ -225,6 +267,15 @@ func (server *Cluster) RunCommand(ctx context.Context, command string, user *Use
 		}
 		user.Mutex.Unlock()
 
+		if reservation, ok := server.rooms.Get(target); ok && reservation.GameServer.IsRunning() {
+			_, err := user.Connect(reservation.GameServer)
+			if err != nil {
+				return true, "", err
+			}
+
+			return true, "", nil
+		}
+
 		for _, gameServer := range server.manager.Servers {
 			if !gameServer.IsReference(target) || !gameServer.IsRunning() {
 				continue
@@ -274,16 +325,284 @@ func (server *Cluster) RunCommand(ctx context.Context, command string, user *Use
 		server.matches.Dequeue(user)
 		return true, "", nil
 
+	case "queue":
+		if len(args) != 2 {
+			return true, "", errors.New("queue takes a single argument: the queue name")
+		}
+
+		if err := server.matches.Queue(user, args[1]); err != nil {
+			return true, "", err
+		}
+
+		return true, "", nil
+
+	case "leave":
+		server.matches.Dequeue(user)
+		return true, "", nil
+
+	case "status":
+		user.SendServerMessage(server.matches.Status(user))
+		return true, "", nil
+
+	case "ready":
+		if err := server.matches.Ready(user); err != nil {
+			return true, "", err
+		}
+
+		return true, "", nil
+
+	case "matchresult":
+		isAdmin, err := server.IsAdmin(ctx, user)
+		if err != nil {
+			return true, "", err
+		}
+		if !isAdmin {
+			return true, "", fmt.Errorf("you are not an operator")
+		}
+
+		if len(args) != 4 {
+			return true, "", errors.New("matchresult takes a queue name, a winner auth name, and a loser auth name")
+		}
+
+		if err := server.matches.ReportResult(args[1], args[2], args[3]); err != nil {
+			return true, "", err
+		}
+
+		return true, "", nil
+
 	case "home":
+		identity := server.settingsIdentity(ctx, user)
+		if home, ok, _ := server.settings.Get(identity, "home_space"); ok && home != "" {
+			space, err := server.spaces.SearchSpace(ctx, home)
+			if err == nil && space != nil {
+				instance, err := server.spaces.StartSpace(ctx, home)
+				if err != nil {
+					return true, "", err
+				}
+
+				_, err = user.ConnectToSpace(instance.Server, instance.Space.GetID())
+				return true, "", err
+			}
+		}
+
 		server.GoHome(server.serverCtx, user)
 		return true, "", nil
 
+	case "set":
+		if len(args) < 3 {
+			return true, "", errors.New("set takes a key and a value")
+		}
+
+		key := args[1]
+		value := strings.Join(args[2:], " ")
+
+		identity := server.settingsIdentity(ctx, user)
+		if err := server.settings.Set(identity, key, value); err != nil {
+			return true, "", err
+		}
+
+		if key == "alias" && user.Server != nil {
+			user.Server.SendCommand(fmt.Sprintf("setname %d %s", user.GetClientNum(), value))
+		}
+
+		return true, "", nil
+
+	case "get":
+		if len(args) != 2 {
+			return true, "", errors.New("get takes a single key")
+		}
+
+		identity := server.settingsIdentity(ctx, user)
+		value, ok, err := server.settings.Get(identity, args[1])
+		if err != nil {
+			return true, "", err
+		}
+
+		if !ok {
+			user.SendServerMessage(fmt.Sprintf("'%s' is not set", args[1]))
+			return true, "", nil
+		}
+
+		user.SendServerMessage(fmt.Sprintf("%s = %s", args[1], value))
+		return true, "", nil
+
+	case "profile":
+		identity := server.settingsIdentity(ctx, user)
+		if len(args) > 1 {
+			identity = "auth:" + args[1]
+		}
+
+		values, err := server.settings.All(identity)
+		if err != nil {
+			return true, "", err
+		}
+
+		if len(values) == 0 {
+			user.SendServerMessage("no settings found")
+			return true, "", nil
+		}
+
+		for key, value := range values {
+			user.SendServerMessage(fmt.Sprintf("%s: %s", key, value))
+		}
+
+		return true, "", nil
+
+	case "reserve":
+		if len(args) != 2 {
+			return true, "", errors.New("reserve takes a single argument")
+		}
+
+		if user.Server == nil {
+			return true, "", errors.New("you are not connected to a server")
+		}
+
+		reservation, err := server.rooms.Reserve(args[1], user.Server, user.Connection.Host(), true)
+		if err != nil {
+			return true, "", err
+		}
+
+		user.SendServerMessage(fmt.Sprintf("this room is now reachable at %s", server.rooms.URL(reservation.Code)))
+		return true, "", nil
+
+	case "rooms":
+		rooms := server.rooms.List()
+		if len(rooms) == 0 {
+			user.SendServerMessage("there are no public rooms right now")
+			return true, "", nil
+		}
+
+		for _, reservation := range rooms {
+			reservation.GameServer.Mutex.Lock()
+			numClients := reservation.GameServer.NumClients
+			reservation.GameServer.Mutex.Unlock()
+
+			user.SendServerMessage(fmt.Sprintf("%s: %d player(s) — %s", reservation.Code, numClients, server.rooms.URL(reservation.Code)))
+		}
+
+		return true, "", nil
+
+	case "kick":
+		isAdmin, err := server.IsAdmin(ctx, user)
+		if err != nil {
+			return true, "", err
+		}
+		if !isAdmin {
+			return true, "", fmt.Errorf("you are not an operator")
+		}
+
+		if len(args) < 2 {
+			return true, "", errors.New("kick takes a target")
+		}
+
+		if user.Server == nil {
+			return true, "", errors.New("you are not connected to a server")
+		}
+
+		user.Server.SendCommand(fmt.Sprintf("kick %s", args[1]))
+		return true, "", nil
+
+	case "ban":
+		isAdmin, err := server.IsAdmin(ctx, user)
+		if err != nil {
+			return true, "", err
+		}
+		if !isAdmin {
+			return true, "", fmt.Errorf("you are not an operator")
+		}
+
+		if len(args) < 2 {
+			return true, "", errors.New("ban takes a target and an optional duration")
+		}
+
+		duration := time.Duration(0)
+		if len(args) > 2 {
+			duration, err = time.ParseDuration(args[2])
+			if err != nil {
+				return true, "", fmt.Errorf("invalid duration '%s'", args[2])
+			}
+		}
+
+		issuedBy := user.Connection.Host()
+
+		banTarget, key := banTargetFor(args[1])
+		if err := server.bans.Ban(banTarget, key, issuedBy, "", duration); err != nil {
+			return true, "", err
+		}
+
+		// A fingerprint isn't something the game server's own "kick"
+		// command understands, so only try to drop the target live for
+		// the identifiers it does.
+		if user.Server != nil && banTarget != auth.BanTargetFingerprint {
+			user.Server.SendCommand(fmt.Sprintf("kick %s", key))
+		}
+
+		return true, "", nil
+
+	case "unban":
+		isAdmin, err := server.IsAdmin(ctx, user)
+		if err != nil {
+			return true, "", err
+		}
+		if !isAdmin {
+			return true, "", fmt.Errorf("you are not an operator")
+		}
+
+		if len(args) < 2 {
+			return true, "", errors.New("unban takes a target")
+		}
+
+		banTarget, key := banTargetFor(args[1])
+		if err := server.bans.Unban(banTarget, key); err != nil {
+			return true, "", err
+		}
+
+		return true, "", nil
+
+	case "banlist":
+		isAdmin, err := server.IsAdmin(ctx, user)
+		if err != nil {
+			return true, "", err
+		}
+		if !isAdmin {
+			return true, "", fmt.Errorf("you are not an operator")
+		}
+
+		for _, entry := range server.bans.List() {
+			message := fmt.Sprintf(
+				"%s (%s) banned by %s at %s",
+				entry.Key,
+				entry.Target,
+				entry.IssuedBy,
+				entry.IssuedAt.Format(time.RFC3339),
+			)
+			if !entry.ExpiresAt.IsZero() {
+				message += fmt.Sprintf(", expires %s", entry.ExpiresAt.Format(time.RFC3339))
+			}
+			user.SendServerMessage(message)
+		}
+
+		return true, "", nil
+
 	case "help":
 		messages := []string{
 			fmt.Sprintf("%s: create a private game", game.Blue("#creategame")),
 			fmt.Sprintf("%s: join a Sour game server by room code", game.Blue("#join [code]")),
+			fmt.Sprintf("%s: request a memorable room code for your server", game.Blue("#reserve <code>")),
+			fmt.Sprintf("%s: list active public rooms", game.Blue("#rooms")),
 			fmt.Sprintf("%s: queue for a duel", game.Blue("#duel")),
 			fmt.Sprintf("%s: leave the duel queue", game.Blue("#stopduel")),
+			fmt.Sprintf("%s: queue for a named matchmaking queue", game.Blue("#queue <name>")),
+			fmt.Sprintf("%s: leave your current queue", game.Blue("#leave")),
+			fmt.Sprintf("%s: show your current queue status", game.Blue("#status")),
+			fmt.Sprintf("%s: confirm you're ready for a found match", game.Blue("#ready")),
+			fmt.Sprintf("%s: report a match outcome to update ratings (operator only)", game.Blue("#matchresult <queue> <winner> <loser>")),
+			fmt.Sprintf("%s: save a personal setting", game.Blue("#set <key> <value>")),
+			fmt.Sprintf("%s: read back a personal setting", game.Blue("#get <key>")),
+			fmt.Sprintf("%s: show your (or another player's) profile", game.Blue("#profile [name]")),
+			fmt.Sprintf("%s: kick a player from your space (operator only)", game.Blue("#kick <target>")),
+			fmt.Sprintf("%s: ban a player by IP, auth name, or fp:<fingerprint> (operator only)", game.Blue("#ban <target> [duration]")),
+			fmt.Sprintf("%s: list active bans (operator only)", game.Blue("#banlist")),
 		}
 
 		for _, message := range messages {
@@ -293,6 +612,10 @@ func (server *Cluster) RunCommand(ctx context.Context, command string, user *Use
 		return true, "", nil
 	}
 
+	if client, ok := server.plugins.Find(args[0]); ok {
+		return server.invokePlugin(ctx, client, args, user)
+	}
+
 	return false, "", nil
 }
 
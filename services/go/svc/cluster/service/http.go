@@ -0,0 +1,23 @@
+package service
+
+import "net/http"
+
+// RegisterAPIRoutes wires the cluster's read-only discovery endpoints
+// onto mux. Serve calls this for the cluster's own HTTP server; it's
+// exported separately so an embedder can fold these routes into a mux
+// of its own instead.
+func (server *Cluster) RegisterAPIRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/api/rooms", server.RoomsAPIHandler)
+	mux.HandleFunc("/api/profile", server.ProfileAPIHandler)
+}
+
+// Serve starts the cluster's HTTP API on addr and blocks until it
+// exits. It's the one caller RegisterAPIRoutes actually has today; a
+// deployment that wants to share a mux with other routes should call
+// RegisterAPIRoutes directly instead of Serve.
+func (server *Cluster) Serve(addr string) error {
+	mux := http.NewServeMux()
+	server.RegisterAPIRoutes(mux)
+
+	return http.ListenAndServe(addr, mux)
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+
+	"github.com/cfoust/sour/pkg/plugin"
+)
+
+// invokePlugin forwards an unrecognized command to the plugin that
+// claimed it, and applies any server_commands it hands back. It
+// respects ctx's deadline, which RunCommandWithTimeout already bounds
+// to ten seconds; InvokeContext tears down the plugin connection on
+// timeout instead of leaking a goroutine on the abandoned call.
+func (server *Cluster) invokePlugin(ctx context.Context, client *plugin.Client, args []string, user *User) (bool, string, error) {
+	isOwner, _ := user.IsOwner(ctx)
+	authID, _ := user.AuthID(ctx)
+
+	serverRef := ""
+	if user.Server != nil {
+		serverRef = user.Server.Reference()
+	}
+
+	request := plugin.InvokeArgs{
+		Command:   args[0],
+		Args:      args[1:],
+		UserID:    authID,
+		Host:      user.Connection.Host(),
+		ServerRef: serverRef,
+		IsOwner:   isOwner,
+	}
+
+	reply, err := client.InvokeContext(ctx, request)
+	if err != nil {
+		return true, "", err
+	}
+
+	if user.Server != nil {
+		for _, command := range reply.ServerCommands {
+			user.Server.SendCommand(command)
+		}
+	}
+
+	return reply.Handled, reply.Response, nil
+}
@@ -0,0 +1,26 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// ProfileAPIHandler serves a player's stored settings as read-only JSON,
+// so a web UI can render a profile page. The auth identity is taken
+// from the "id" query parameter, e.g. /api/profile?id=auth:someone.
+func (server *Cluster) ProfileAPIHandler(w http.ResponseWriter, r *http.Request) {
+	identity := r.URL.Query().Get("id")
+	if identity == "" {
+		http.Error(w, "missing id", http.StatusBadRequest)
+		return
+	}
+
+	values, err := server.settings.All(identity)
+	if err != nil {
+		http.Error(w, "failed to load profile", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(values)
+}
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// roomInfo is the JSON shape returned by /api/rooms.
+type roomInfo struct {
+	Code       string `json:"code"`
+	URL        string `json:"url"`
+	NumClients int    `json:"num_clients"`
+}
+
+// RoomsAPIHandler serves the list of active public rooms for
+// out-of-game discovery, e.g. a web UI's server browser.
+func (server *Cluster) RoomsAPIHandler(w http.ResponseWriter, r *http.Request) {
+	rooms := server.rooms.List()
+
+	infos := make([]roomInfo, 0, len(rooms))
+	for _, reservation := range rooms {
+		reservation.GameServer.Mutex.Lock()
+		numClients := reservation.GameServer.NumClients
+		reservation.GameServer.Mutex.Unlock()
+
+		infos = append(infos, roomInfo{
+			Code:       reservation.Code,
+			URL:        server.rooms.URL(reservation.Code),
+			NumClients: numClients,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(infos)
+}
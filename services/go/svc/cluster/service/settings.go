@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+
+	"github.com/repeale/fp-go/option"
+)
+
+// settingsIdentity returns the key under which user's settings are
+// stored: their Sour auth identity if they have one, otherwise their
+// connection host, so anonymous players still get a (less durable)
+// profile.
+func (server *Cluster) settingsIdentity(ctx context.Context, user *User) string {
+	if authID, err := user.AuthID(ctx); err == nil && authID != "" {
+		return "auth:" + authID
+	}
+
+	return "host:" + user.Connection.Host()
+}
+
+// defaultCreateParams builds CreateParams from a user's default_mode
+// and default_preset settings, for a bare #creategame with no
+// arguments.
+func (server *Cluster) defaultCreateParams(ctx context.Context, user *User) *CreateParams {
+	params := &CreateParams{}
+
+	identity := server.settingsIdentity(ctx, user)
+
+	if mode, ok, _ := server.settings.Get(identity, "default_mode"); ok {
+		params.Mode = getModeNumber(mode)
+	}
+
+	if preset, ok, _ := server.settings.Get(identity, "default_preset"); ok {
+		params.Preset = opt.Some(preset)
+	}
+
+	return params
+}
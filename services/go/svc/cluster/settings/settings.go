@@ -0,0 +1,120 @@
+// Package settings persists per-user preferences (alias, default game
+// mode, home space, etc.) keyed by Sour auth identity, in SQLite.
+package settings
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const maxAliasLength = 32
+
+// aliasPattern restricts aliases to characters that are safe to splice
+// into a raw game server console command (e.g. "setname <num> <alias>")
+// without smuggling in extra arguments or directives. Notably, no
+// whitespace, quotes, or newlines.
+var aliasPattern = regexp.MustCompile(`^[A-Za-z0-9_\-\[\]]{1,32}$`)
+
+// ValidateAlias reports whether value is safe to store (and later
+// forward to a game server) as a player's alias.
+func ValidateAlias(value string) error {
+	if !aliasPattern.MatchString(value) {
+		return fmt.Errorf("alias may only contain letters, numbers, '_', '-', '[' and ']', and must be 1-%d characters", maxAliasLength)
+	}
+
+	return nil
+}
+
+// RecognizedKeys lists the settings the cluster itself understands and
+// acts on. Any other key is still stored, just not interpreted.
+var RecognizedKeys = map[string]bool{
+	"alias":          true,
+	"default_mode":   true,
+	"default_preset": true,
+	"home_space":     true,
+	"hud_color":      true,
+}
+
+// Store is a SQLite-backed key/value store, partitioned by identity.
+type Store struct {
+	db *sql.DB
+}
+
+// NewStore opens (and if necessary creates) the SQLite database at
+// path.
+func NewStore(path string) (*Store, error) {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS settings (
+			identity TEXT NOT NULL,
+			key      TEXT NOT NULL,
+			value    TEXT NOT NULL,
+			PRIMARY KEY (identity, key)
+		)
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize settings table: %w", err)
+	}
+
+	return &Store{db: db}, nil
+}
+
+// Set validates and stores value under key for identity.
+func (store *Store) Set(identity string, key string, value string) error {
+	if key == "alias" {
+		if err := ValidateAlias(value); err != nil {
+			return err
+		}
+	}
+
+	_, err := store.db.Exec(`
+		INSERT INTO settings (identity, key, value) VALUES (?, ?, ?)
+		ON CONFLICT(identity, key) DO UPDATE SET value = excluded.value
+	`, identity, key, value)
+
+	return err
+}
+
+// Get returns the value stored under key for identity, if any.
+func (store *Store) Get(identity string, key string) (string, bool, error) {
+	row := store.db.QueryRow(`SELECT value FROM settings WHERE identity = ? AND key = ?`, identity, key)
+
+	var value string
+	err := row.Scan(&value)
+	if err == sql.ErrNoRows {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+
+	return value, true, nil
+}
+
+// All returns every key/value pair stored for identity, for use by the
+// #profile command and the profile HTTP endpoint.
+func (store *Store) All(identity string) (map[string]string, error) {
+	rows, err := store.db.Query(`SELECT key, value FROM settings WHERE identity = ?`, identity)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	values := make(map[string]string)
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			return nil, err
+		}
+		values[key] = value
+	}
+
+	return values, rows.Err()
+}
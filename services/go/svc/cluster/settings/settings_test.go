@@ -0,0 +1,25 @@
+package settings
+
+import "testing"
+
+func TestValidateAlias(t *testing.T) {
+	valid := []string{"a", "Player_1", "x-[AFK]", "thirtytwocharactersexactlyhereok"}
+	for _, alias := range valid {
+		if err := ValidateAlias(alias); err != nil {
+			t.Errorf("expected %q to be a valid alias, got error: %v", alias, err)
+		}
+	}
+
+	invalid := []string{
+		"",
+		"this alias has spaces",
+		"new\nline",
+		"semicolon;injected",
+		"waaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaay too long for thirty two chars",
+	}
+	for _, alias := range invalid {
+		if err := ValidateAlias(alias); err == nil {
+			t.Errorf("expected %q to be rejected as an alias", alias)
+		}
+	}
+}